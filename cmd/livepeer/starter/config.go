@@ -0,0 +1,108 @@
+package starter
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/BurntSushi/toml"
+)
+
+var configPath = flag.String("config", "", "Path to a TOML config file with starter flag values; explicit CLI flags always take precedence")
+
+// Config mirrors the starter package's CLI flags so an operator can set them
+// from a file instead of passing dozens of flags on the command line.
+type Config struct {
+	EthKeystorePath     string             `toml:"ethKeystorePath"`
+	EthKeystoreAddress  string             `toml:"ethKeystoreAddress"`
+	EthOrchAddr         string             `toml:"ethOrchAddr"`
+	EthKeystoreIndex    int                `toml:"ethKeystoreIndex"`
+	PricePerBroadcaster string             `toml:"pricePerBroadcaster"`
+	Broadcasters        []BroadcasterPrice `toml:"broadcasters"`
+}
+
+// LoadConfig parses the TOML config file at path into a Config. The returned
+// toml.MetaData records which fields were actually present in the file, so
+// mergeConfig can tell a field explicitly set to its zero value apart from
+// one simply absent from the file.
+func LoadConfig(path string) (Config, toml.MetaData, error) {
+	var cfg Config
+	md, err := toml.DecodeFile(path, &cfg)
+	if err != nil {
+		return Config{}, toml.MetaData{}, fmt.Errorf("error parsing -config file %s: %v", path, err)
+	}
+	return cfg, md, nil
+}
+
+// mergeConfig applies cfg's values to any flag not explicitly set on the
+// command line. Precedence is: explicit CLI flag > config file value > the
+// flag's built-in default. Both "explicitly set on the CLI" (flag.Visit) and
+// "explicitly set in the file" (md.IsDefined) are tracked directly, rather
+// than by comparing against zero values, so a field set to its zero value in
+// the file still takes precedence over the flag's default.
+func mergeConfig(cfg Config, md toml.MetaData) {
+	explicitFlag := map[string]bool{}
+	flag.Visit(func(f *flag.Flag) {
+		explicitFlag[f.Name] = true
+	})
+
+	if !explicitFlag["ethKeystorePath"] && md.IsDefined("ethKeystorePath") {
+		*ethKeystorePath = cfg.EthKeystorePath
+	}
+	if !explicitFlag["ethKeystoreAddress"] && md.IsDefined("ethKeystoreAddress") {
+		*ethKeystoreAddress = cfg.EthKeystoreAddress
+	}
+	if !explicitFlag["ethOrchAddr"] && md.IsDefined("ethOrchAddr") {
+		*ethOrchAddr = cfg.EthOrchAddr
+	}
+	if !explicitFlag["ethKeystoreIndex"] && md.IsDefined("ethKeystoreIndex") {
+		*ethKeystoreIndex = cfg.EthKeystoreIndex
+	}
+	if !explicitFlag["pricePerBroadcaster"] && md.IsDefined("pricePerBroadcaster") {
+		*pricePerBroadcaster = cfg.PricePerBroadcaster
+	}
+
+	effectiveBroadcasterPrices = nil
+	if md.IsDefined("broadcasters") && *pricePerBroadcaster == "" {
+		effectiveBroadcasterPrices = cfg.Broadcasters
+	}
+}
+
+// effectiveBroadcasterPrices holds broadcaster prices parsed from the
+// -config file's broadcasters table, used when -pricePerBroadcaster is unset.
+// It is reset on every mergeConfig call so a reload doesn't accumulate stale
+// entries from a previous config file.
+var effectiveBroadcasterPrices []BroadcasterPrice
+
+// loadAndMergeConfig loads the -config file, if one was given, and merges it
+// into the package's flags. It is the single production call site for
+// LoadConfig/mergeConfig and must run before the merged flags are consumed
+// elsewhere in the package (e.g. parseEthKeystorePath, getBroadcasterPrices).
+func loadAndMergeConfig() error {
+	if *configPath == "" {
+		return nil
+	}
+
+	cfg, md, err := LoadConfig(*configPath)
+	if err != nil {
+		return err
+	}
+
+	mergeConfig(cfg, md)
+	return nil
+}
+
+// DumpConfig writes the fully-resolved effective configuration (CLI flags
+// merged over the -config file) as TOML, for debugging what precedence
+// resolved to
+func DumpConfig(w io.Writer) error {
+	cfg := Config{
+		EthKeystorePath:     *ethKeystorePath,
+		EthKeystoreAddress:  *ethKeystoreAddress,
+		EthOrchAddr:         *ethOrchAddr,
+		EthKeystoreIndex:    *ethKeystoreIndex,
+		PricePerBroadcaster: *pricePerBroadcaster,
+		Broadcasters:        effectiveBroadcasterPrices,
+	}
+	return toml.NewEncoder(w).Encode(cfg)
+}
@@ -0,0 +1,196 @@
+package starter
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/golang/glog"
+)
+
+var (
+	ethKeystoreAddress = flag.String("ethKeystoreAddress", "", "Address of the Ethereum account to use, for use with -ethKeystorePath pointing to a directory containing multiple keys")
+	ethOrchAddr        = flag.String("ethOrchAddr", "", "Alias for -ethKeystoreAddress")
+	ethKeystoreIndex   = flag.Int("ethKeystoreIndex", -1, "0-based index (stable, lexical by filename) of the keystore file to use, for use with -ethKeystorePath pointing to a directory containing multiple keys")
+)
+
+// ErrAmbiguousAddress is returned by keystoreCache.FileFor when more than one keyfile
+// in the cached directory claims the requested address, so the caller can report all
+// of the conflicting paths and let the user delete the duplicates
+type ErrAmbiguousAddress struct {
+	Address ethcommon.Address
+	Files   []string
+}
+
+func (e *ErrAmbiguousAddress) Error() string {
+	return "multiple keystore files found for address " + e.Address.Hex() + ": " + strings.Join(e.Files, ", ")
+}
+
+// keystoreCache indexes every valid V3 keyfile in a keystore directory by address,
+// so a node can unlock a specific account without the caller needing to know its
+// filename up front. This mirrors the address-cache approach go-ethereum's
+// accounts package uses in place of filename-encoded addresses.
+type keystoreCache struct {
+	dir string
+
+	mu     sync.RWMutex
+	byAddr map[ethcommon.Address][]string
+}
+
+// newKeystoreCache builds a keystoreCache for dir, performing an initial scan
+func newKeystoreCache(dir string) (*keystoreCache, error) {
+	c := &keystoreCache{dir: dir}
+	if err := c.Refresh(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Refresh re-walks the keystore directory and rebuilds the address index. Only
+// the top-level "address" field of each file is decoded; the crypto blob and
+// password are never touched here.
+func (c *keystoreCache) Refresh() error {
+	byAddr := make(map[ethcommon.Address][]string)
+
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		path := filepath.Join(c.dir, entry.Name())
+		addr, err := addressFromKeyfile(path)
+		if err != nil {
+			continue
+		}
+
+		byAddr[addr] = append(byAddr[addr], path)
+	}
+
+	c.mu.Lock()
+	c.byAddr = byAddr
+	c.mu.Unlock()
+
+	return nil
+}
+
+// Accounts returns every address with at least one keyfile in the cache
+func (c *keystoreCache) Accounts() []ethcommon.Address {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	addrs := make([]ethcommon.Address, 0, len(c.byAddr))
+	for addr := range c.byAddr {
+		addrs = append(addrs, addr)
+	}
+	return addrs
+}
+
+// keystoreEntry is one keyfile in a keystore directory, as enumerated by
+// keystoreCache.Entries
+type keystoreEntry struct {
+	Address ethcommon.Address
+	Path    string
+}
+
+// Entries returns every keyfile in the cache in stable order (lexical by
+// path, which is lexical by filename since all entries share a directory).
+// The position of an entry in this slice is the index -ethKeystoreIndex
+// selects.
+func (c *keystoreCache) Entries() []keystoreEntry {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	entries := make([]keystoreEntry, 0)
+	for addr, files := range c.byAddr {
+		for _, f := range files {
+			entries = append(entries, keystoreEntry{Address: addr, Path: f})
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Path < entries[j].Path })
+	return entries
+}
+
+// logKeystoreEntries logs the full ordered (index, address, path) list for a
+// keystore directory, so operators can script non-interactive selection
+// (e.g. via -ethKeystoreIndex) in containerized deployments
+func logKeystoreEntries(entries []keystoreEntry) {
+	for i, e := range entries {
+		glog.Infof("Keystore: [%d] %s (%s)", i, e.Address.Hex(), e.Path)
+	}
+}
+
+// FileFor returns the keyfile path for addr, or ErrAmbiguousAddress if more than
+// one keyfile claims the same address
+func (c *keystoreCache) FileFor(addr ethcommon.Address) (string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	files := c.byAddr[addr]
+	switch len(files) {
+	case 0:
+		return "", errors.New("no keystore file found for address " + addr.Hex())
+	case 1:
+		return files[0], nil
+	default:
+		return "", &ErrAmbiguousAddress{Address: addr, Files: files}
+	}
+}
+
+// resolveKeystoreAddressFlag returns the account requested via -ethKeystoreAddress
+// or its -ethOrchAddr alias, preferring -ethKeystoreAddress if both are set
+func resolveKeystoreAddressFlag() string {
+	if *ethKeystoreAddress != "" {
+		return *ethKeystoreAddress
+	}
+	return *ethOrchAddr
+}
+
+// selectKeystoreAccount resolves the account requested via -ethKeystoreAddress
+// or its -ethOrchAddr alias against cache, failing if the address is absent or
+// ambiguous
+func selectKeystoreAccount(cache *keystoreCache) (ethcommon.Address, error) {
+	addr := ethcommon.HexToAddress(resolveKeystoreAddressFlag())
+	if _, err := cache.FileFor(addr); err != nil {
+		return ethcommon.Address{}, err
+	}
+	return addr, nil
+}
+
+// selectKeystoreEntry resolves which keystore entry to unlock out of a
+// directory holding more than one key. -ethKeystoreAddress (and its
+// -ethOrchAddr alias) take precedence over -ethKeystoreIndex; if both are
+// supplied the index is ignored with a warning. If neither is supplied,
+// selection fails fast with the full enumerated list rather than picking
+// arbitrarily.
+func selectKeystoreEntry(cache *keystoreCache, entries []keystoreEntry) (ethcommon.Address, error) {
+	addrFlag := resolveKeystoreAddressFlag()
+	hasIndex := *ethKeystoreIndex >= 0
+
+	if addrFlag != "" {
+		if hasIndex {
+			glog.Warningf("Both -ethKeystoreAddress and -ethKeystoreIndex were supplied; using the address and ignoring the index")
+		}
+		return selectKeystoreAccount(cache)
+	}
+
+	if hasIndex {
+		if *ethKeystoreIndex >= len(entries) {
+			return ethcommon.Address{}, fmt.Errorf("-ethKeystoreIndex %d is out of range; directory contains %d keys", *ethKeystoreIndex, len(entries))
+		}
+		return entries[*ethKeystoreIndex].Address, nil
+	}
+
+	logKeystoreEntries(entries)
+	return ethcommon.Address{}, errors.New("-ethKeystorePath directory contains multiple keys; specify -ethKeystoreAddress or -ethKeystoreIndex")
+}
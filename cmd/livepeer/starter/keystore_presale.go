@@ -0,0 +1,153 @@
+package starter
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/sha3"
+)
+
+// keystoreKind identifies which decrypter a keystorePath should be routed to
+type keystoreKind int
+
+const (
+	kindV3 keystoreKind = iota
+	kindPresale
+)
+
+// presaleWallet is the pre-sale Ethereum wallet JSON format, predating the V3
+// keystore standard
+type presaleWallet struct {
+	EncSeed string `json:"encseed"`
+	EthAddr string `json:"ethaddr"`
+	Email   string `json:"email"`
+	BTCAddr string `json:"btcaddr"`
+}
+
+// isPresaleWallet reports whether raw looks like a pre-sale wallet (it has an
+// "encseed" field and no "crypto" field) rather than a V3 keystore file
+func isPresaleWallet(raw map[string]json.RawMessage) bool {
+	_, hasEncSeed := raw["encseed"]
+	_, hasCrypto := raw["crypto"]
+	return hasEncSeed && !hasCrypto
+}
+
+// keyfileKind sniffs keyfile's top-level fields to determine whether it is a
+// V3 keystore file or a pre-sale wallet
+func keyfileKind(keyfile string) (keystoreKind, error) {
+	f, err := os.Open(keyfile)
+	if err != nil {
+		return kindV3, errors.New("provided -ethKeystorePath was not found")
+	}
+	defer f.Close()
+
+	var raw map[string]json.RawMessage
+	if err := json.NewDecoder(f).Decode(&raw); err != nil {
+		return kindV3, errors.New("error parsing address from keyfile")
+	}
+
+	if isPresaleWallet(raw) {
+		return kindPresale, nil
+	}
+	return kindV3, nil
+}
+
+// presaleAddress decodes the ethaddr field of a pre-sale wallet, without
+// decrypting the seed
+func presaleAddress(keyfile string) (ethcommon.Address, error) {
+	w, err := readPresaleWallet(keyfile)
+	if err != nil {
+		return ethcommon.Address{}, err
+	}
+
+	addr := w.EthAddr
+	if len(addr) >= 2 && addr[:2] == "0x" {
+		addr = addr[2:]
+	}
+	return ethcommon.BytesToAddress(ethcommon.FromHex(addr)), nil
+}
+
+func readPresaleWallet(keyfile string) (presaleWallet, error) {
+	f, err := os.Open(keyfile)
+	if err != nil {
+		return presaleWallet{}, errors.New("provided -ethKeystorePath was not found")
+	}
+	defer f.Close()
+
+	var w presaleWallet
+	if err := json.NewDecoder(f).Decode(&w); err != nil || w.EthAddr == "" {
+		return presaleWallet{}, errors.New("error parsing address from keyfile")
+	}
+	return w, nil
+}
+
+// decryptPresaleKey recovers the ECDSA private key from a pre-sale wallet
+// file, deriving the AES-128-CBC key via PBKDF2-HMAC-SHA256(password,
+// password, 2000, 16) and taking keccak256 of the decrypted seed as the
+// private key. It refuses the result if the derived address does not match
+// the wallet's ethaddr field.
+func decryptPresaleKey(keyfile, password string) ([]byte, ethcommon.Address, error) {
+	w, err := readPresaleWallet(keyfile)
+	if err != nil {
+		return nil, ethcommon.Address{}, err
+	}
+
+	seed, err := hex.DecodeString(w.EncSeed)
+	if err != nil {
+		return nil, ethcommon.Address{}, errors.New("error decoding presale encseed")
+	}
+	if len(seed) < aes.BlockSize {
+		return nil, ethcommon.Address{}, errors.New("presale encseed too short")
+	}
+
+	key := pbkdf2.Key([]byte(password), []byte(password), 2000, 16, sha256.New)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, ethcommon.Address{}, err
+	}
+
+	iv, ciphertext := seed[:aes.BlockSize], seed[aes.BlockSize:]
+	plaintext := make([]byte, len(ciphertext))
+	// The pre-sale format encrypts with auto-padding disabled (see
+	// ethereumjs-wallet's fromEtherWallet), so the raw CBC output is hashed
+	// directly rather than PKCS7-unpadded.
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plaintext, ciphertext)
+
+	privKey := keccak256(plaintext)
+	derived, err := addressFromPrivateKey(privKey)
+	if err != nil {
+		return nil, ethcommon.Address{}, err
+	}
+
+	wantAddr := w.EthAddr
+	if len(wantAddr) >= 2 && wantAddr[:2] == "0x" {
+		wantAddr = wantAddr[2:]
+	}
+	if derived != ethcommon.BytesToAddress(ethcommon.FromHex(wantAddr)) {
+		return nil, ethcommon.Address{}, errors.New("presale wallet password did not derive the expected address")
+	}
+
+	return privKey, derived, nil
+}
+
+func addressFromPrivateKey(privKey []byte) (ethcommon.Address, error) {
+	ecdsaKey, err := crypto.ToECDSA(privKey)
+	if err != nil {
+		return ethcommon.Address{}, errors.New("invalid presale private key")
+	}
+	return crypto.PubkeyToAddress(ecdsaKey.PublicKey), nil
+}
+
+func keccak256(data []byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	h.Write(data)
+	return h.Sum(nil)
+}
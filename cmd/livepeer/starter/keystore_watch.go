@@ -0,0 +1,152 @@
+package starter
+
+import (
+	"os"
+	"time"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/fsnotify/fsnotify"
+	"github.com/golang/glog"
+)
+
+const (
+	// watchDebounce coalesces the write-then-rename pattern many editors and
+	// key-generation tools use into a single refresh
+	watchDebounce = 500 * time.Millisecond
+
+	// pollInterval is used on platforms/filesystems where inotify-style events
+	// are unavailable or unreliable (network mounts, fuse)
+	pollInterval = 2 * time.Second
+)
+
+// keystoreWatcher keeps a keystoreCache in sync with on-disk changes to its
+// directory for as long as the owning node is running, so keys dropped into
+// or removed from the directory are picked up without a restart. This is the
+// same role geth's accounts/watch.go plays for its address cache.
+type keystoreWatcher struct {
+	cache *keystoreCache
+	done  chan struct{}
+}
+
+// watchKeystoreCache starts a background goroutine that refreshes cache on
+// directory changes. The caller owns the returned watcher's lifecycle and
+// must call Stop when the node shuts down.
+func watchKeystoreCache(cache *keystoreCache) *keystoreWatcher {
+	w := &keystoreWatcher{cache: cache, done: make(chan struct{})}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		glog.Warningf("Could not start keystore filesystem watcher, falling back to polling: %v", err)
+		go w.poll()
+		return w
+	}
+
+	if err := watcher.Add(cache.dir); err != nil {
+		glog.Warningf("Could not watch keystore directory %s, falling back to polling: %v", cache.dir, err)
+		watcher.Close()
+		go w.poll()
+		return w
+	}
+
+	go w.watch(watcher)
+	return w
+}
+
+func (w *keystoreWatcher) watch(watcher *fsnotify.Watcher) {
+	defer watcher.Close()
+
+	var debounce *time.Timer
+	refresh := func() {
+		before := accountSet(w.cache.Accounts())
+		if err := w.cache.Refresh(); err != nil {
+			glog.Errorf("Error refreshing keystore cache: %v", err)
+			return
+		}
+		logAccountChanges(before, accountSet(w.cache.Accounts()))
+	}
+
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if event.Op&(fsnotify.Create|fsnotify.Write|fsnotify.Remove|fsnotify.Rename) == 0 {
+				continue
+			}
+			if debounce == nil {
+				debounce = time.AfterFunc(watchDebounce, refresh)
+			} else {
+				debounce.Reset(watchDebounce)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			glog.Errorf("Keystore filesystem watcher error: %v", err)
+		case <-w.done:
+			return
+		}
+	}
+}
+
+func (w *keystoreWatcher) poll() {
+	stat, _ := os.Stat(w.cache.dir)
+	var lastMod time.Time
+	if stat != nil {
+		lastMod = stat.ModTime()
+	}
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			stat, err := os.Stat(w.cache.dir)
+			if err != nil {
+				continue
+			}
+			if stat.ModTime().Equal(lastMod) {
+				continue
+			}
+			lastMod = stat.ModTime()
+
+			before := accountSet(w.cache.Accounts())
+			if err := w.cache.Refresh(); err != nil {
+				glog.Errorf("Error refreshing keystore cache: %v", err)
+				continue
+			}
+			logAccountChanges(before, accountSet(w.cache.Accounts()))
+		case <-w.done:
+			return
+		}
+	}
+}
+
+// Stop shuts down the watcher goroutine. Safe to call once from the owning
+// LivepeerNode's stop path.
+func (w *keystoreWatcher) Stop() {
+	close(w.done)
+}
+
+func accountSet(accts []ethcommon.Address) map[ethcommon.Address]bool {
+	set := make(map[ethcommon.Address]bool, len(accts))
+	for _, a := range accts {
+		set[a] = true
+	}
+	return set
+}
+
+func logAccountChanges(before, after map[ethcommon.Address]bool) {
+	for addr := range after {
+		if !before[addr] {
+			glog.Infof("Keystore: detected new account %s", addr.Hex())
+		}
+	}
+	for addr := range before {
+		if !after[addr] {
+			glog.Infof("Keystore: account %s removed", addr.Hex())
+		}
+	}
+}
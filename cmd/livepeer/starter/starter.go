@@ -0,0 +1,209 @@
+package starter
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/golang/glog"
+	"github.com/livepeer/go-livepeer/common"
+	"github.com/livepeer/go-livepeer/core"
+)
+
+var (
+	ethKeystorePath     = flag.String("ethKeystorePath", "", "Path to an Ethereum keystore file or directory")
+	pricePerBroadcaster = flag.String("pricePerBroadcaster", "", `JSON list of broadcaster prices, e.g. {"broadcasters":[{"ethaddress":"0x...","priceperunit":0,"pixelsperunit":1}]}`)
+)
+
+// BroadcasterPrice is the per-broadcaster price set via -pricePerBroadcaster or a config file's broadcasters table
+type BroadcasterPrice struct {
+	EthAddress    string `json:"ethaddress" toml:"ethaddress"`
+	PricePerUnit  int64  `json:"priceperunit" toml:"priceperunit"`
+	PixelsPerUnit int64  `json:"pixelsperunit" toml:"pixelsperunit"`
+}
+
+type broadcasterPrices struct {
+	Broadcasters []BroadcasterPrice `json:"broadcasters"`
+}
+
+func getBroadcasterPrices(broadcasterPricesFlag string) []BroadcasterPrice {
+	var prices broadcasterPrices
+	if err := json.Unmarshal([]byte(broadcasterPricesFlag), &prices); err != nil {
+		glog.Errorf("Error parsing broadcaster prices: %v", err)
+		return nil
+	}
+
+	return prices.Broadcasters
+}
+
+// isLocalURL returns true if the host of the provided URL is a loopback address or "localhost"
+func isLocalURL(addr string) (bool, error) {
+	u, err := url.ParseRequestURI(addr)
+	if err != nil {
+		return false, err
+	}
+
+	hostname := u.Hostname()
+	if ip := net.ParseIP(hostname); ip != nil {
+		return ip.IsLoopback(), nil
+	}
+
+	return hostname == "localhost", nil
+}
+
+// keystorePath describes the result of parsing -ethKeystorePath: either a single
+// keyfile with its address already resolved, or a directory of keyfiles indexed
+// by the cache below
+type keystorePath struct {
+	path    string
+	address ethcommon.Address
+	kind    keystoreKind
+	cache   *keystoreCache
+}
+
+// parseEthKeystorePath resolves -ethKeystorePath to either a specific keyfile
+// (decoding its address ahead of time) or a directory of keyfiles. For a
+// directory, every valid keyfile is indexed into a keystoreCache so the
+// caller can unlock a specific account without knowing its filename; the
+// account is auto-selected when the directory holds exactly one key, or
+// resolved from -ethKeystoreAddress/-ethOrchAddr or -ethKeystoreIndex when it
+// holds more. A
+// keyfile may be either a V3 keystore file or a pre-sale wallet; kind
+// records which so callers route to the right decrypter.
+func parseEthKeystorePath(ethKeystorePath string) (keystorePath, error) {
+	stat, err := os.Stat(ethKeystorePath)
+	if os.IsNotExist(err) {
+		return keystorePath{}, errors.New("provided -ethKeystorePath was not found")
+	}
+
+	if stat.IsDir() {
+		return parseEthKeystoreDir(ethKeystorePath)
+	}
+
+	kind, err := keyfileKind(ethKeystorePath)
+	if err != nil {
+		return keystorePath{}, err
+	}
+
+	if kind == kindPresale {
+		addr, err := presaleAddress(ethKeystorePath)
+		if err != nil {
+			return keystorePath{}, err
+		}
+		return keystorePath{address: addr, kind: kindPresale}, nil
+	}
+
+	addr, err := addressFromKeyfile(ethKeystorePath)
+	if err != nil {
+		return keystorePath{}, err
+	}
+
+	return keystorePath{address: addr, kind: kindV3}, nil
+}
+
+func parseEthKeystoreDir(dir string) (keystorePath, error) {
+	cache, err := newKeystoreCache(dir)
+	if err != nil {
+		return keystorePath{}, err
+	}
+
+	kp := keystorePath{path: dir, cache: cache}
+
+	switch accts := cache.Accounts(); {
+	case resolveKeystoreAddressFlag() != "" || *ethKeystoreIndex >= 0:
+		addr, err := selectKeystoreEntry(cache, cache.Entries())
+		if err != nil {
+			return keystorePath{}, err
+		}
+		kp.address = addr
+	case len(accts) == 1:
+		kp.address = accts[0]
+	case len(accts) > 1:
+		logKeystoreEntries(cache.Entries())
+		return keystorePath{}, errors.New("-ethKeystorePath directory contains multiple keys; specify -ethKeystoreAddress or -ethKeystoreIndex")
+	}
+
+	return kp, nil
+}
+
+// addressFromKeyfile decodes only the top-level "address" field of a V3 keystore
+// file, never touching the encrypted crypto blob
+func addressFromKeyfile(keyfile string) (ethcommon.Address, error) {
+	f, err := os.Open(keyfile)
+	if err != nil {
+		return ethcommon.Address{}, errors.New("provided -ethKeystorePath was not found")
+	}
+	defer f.Close()
+
+	var key struct {
+		Address string `json:"address"`
+	}
+	if err := json.NewDecoder(f).Decode(&key); err != nil || key.Address == "" {
+		return ethcommon.Address{}, errors.New("error parsing address from keyfile")
+	}
+
+	addr := strings.TrimPrefix(key.Address, "0x")
+	return ethcommon.BytesToAddress(ethcommon.FromHex(addr)), nil
+}
+
+// SetupKeystore is the starter package's entry point for resolving
+// -ethKeystorePath: it applies -config (if given) over the package's flags,
+// parses the resulting -ethKeystorePath, and, when it resolves to a
+// directory, starts the live-reload watcher tied to n's lifecycle. Callers
+// serving the node's /status response should include keystoreStatus(kp).
+func SetupKeystore(n *core.LivepeerNode) (keystorePath, error) {
+	if err := loadAndMergeConfig(); err != nil {
+		return keystorePath{}, err
+	}
+
+	kp, err := parseEthKeystorePath(*ethKeystorePath)
+	if err != nil {
+		return keystorePath{}, err
+	}
+
+	startKeystoreWatcher(n, kp)
+
+	return kp, nil
+}
+
+// startKeystoreWatcher starts a background watcher that keeps kp's keystore
+// cache in sync with on-disk changes and ties its lifecycle to n, so it is
+// stopped when the node shuts down. It is a no-op when kp did not resolve to
+// a directory.
+func startKeystoreWatcher(n *core.LivepeerNode, kp keystorePath) {
+	if kp.cache == nil {
+		return
+	}
+
+	w := watchKeystoreCache(kp.cache)
+	n.OnStop(w.Stop)
+}
+
+// keystoreStatus returns the addresses currently known to the keystore cache,
+// for inclusion in the node's /status response. It returns nil when
+// -ethKeystorePath did not resolve to a directory.
+func keystoreStatus(kp keystorePath) []ethcommon.Address {
+	if kp.cache == nil {
+		return nil
+	}
+	return kp.cache.Accounts()
+}
+
+// setupOrchestrator registers the on-chain orchestrator record for orchAddr in the node's database
+func setupOrchestrator(n *core.LivepeerNode, orchAddr ethcommon.Address) error {
+	orch, err := n.Eth.GetTranscoder(orchAddr)
+	if err != nil {
+		return err
+	}
+
+	return n.Database.UpdateOrch(&common.DBOrch{
+		EthereumAddr:      orchAddr.Hex(),
+		ActivationRound:   orch.ActivationRound.Int64(),
+		DeactivationRound: orch.DeactivationRound.Int64(),
+	})
+}
@@ -1,6 +1,7 @@
 package starter
 
 import (
+	"encoding/hex"
 	"errors"
 	"math/big"
 	"os"
@@ -153,6 +154,122 @@ func TestParse_ParseEthKeystorePathValidDirectory(t *testing.T) {
 	assert.True(err == nil)
 }
 
+func writeKeyfile(t *testing.T, dir, name, addr string) string {
+	path := filepath.Join(dir, name)
+	contents := "{\"address\":\"" + addr + "\",\"crypto\":{\"cipher\":\"1\",\"ciphertext\":\"1\",\"cipherparams\":{\"iv\":\"1\"},\"kdf\":\"scrypt\",\"kdfparams\":{\"dklen\":32,\"n\":1,\"p\":1,\"r\":8,\"salt\":\"1\"},\"mac\":\"1\"},\"id\":\"1\",\"version\":3}"
+	require.NoError(t, os.WriteFile(path, []byte(contents), 0644))
+	return path
+}
+
+// TestSetupKeystore_StartsWatcherAndSurfacesStatus exercises the full
+// production path -config/-ethKeystorePath flows through: SetupKeystore
+// resolves the keystore directory, starts the live-reload watcher tied to
+// the node's lifecycle, and keystoreStatus reports the account it found.
+func TestSetupKeystore_StartsWatcherAndSurfacesStatus(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+	tempDir := t.TempDir()
+
+	addr := "0000000000000000000000000000000000000001"
+	writeKeyfile(t, tempDir, "UTC--2023-01-05T00-46-15.000000000Z--"+addr, addr)
+
+	defer func() { *ethKeystorePath = "" }()
+	*ethKeystorePath = tempDir
+
+	dbh, dbraw, err := common.TempDB(t)
+	require.NoError(err)
+	defer dbh.Close()
+	defer dbraw.Close()
+
+	n, err := core.NewLivepeerNode(&eth.StubClient{}, "", dbh)
+	require.NoError(err)
+
+	kp, err := SetupKeystore(n)
+	require.NoError(err)
+	require.NotNil(kp.cache)
+
+	assert.Equal(ethcommon.BytesToAddress(ethcommon.FromHex(addr)), kp.address)
+	assert.Equal([]ethcommon.Address{kp.address}, keystoreStatus(kp))
+}
+
+func TestParse_ParseEthKeystorePathMultipleKeys(t *testing.T) {
+	assert := assert.New(t)
+	tempDir := t.TempDir()
+
+	addr0 := "0000000000000000000000000000000000000001"
+	addr1 := "0000000000000000000000000000000000000002"
+	writeKeyfile(t, tempDir, "UTC--2023-01-05T00-46-15.000000000Z--"+addr0, addr0)
+	writeKeyfile(t, tempDir, "UTC--2023-01-06T00-46-15.000000000Z--"+addr1, addr1)
+
+	defer func() {
+		*ethKeystoreAddress = ""
+		*ethOrchAddr = ""
+		*ethKeystoreIndex = -1
+	}()
+
+	// Ambiguity: neither -ethKeystoreAddress nor -ethKeystoreIndex supplied
+	*ethKeystoreAddress, *ethOrchAddr, *ethKeystoreIndex = "", "", -1
+	_, err := parseEthKeystorePath(tempDir)
+	assert.NotNil(err)
+
+	// Index selection: entries are ordered lexically by filename, so index 0 is addr0
+	*ethKeystoreIndex = 0
+	keystoreInfo, err := parseEthKeystorePath(tempDir)
+	assert.Nil(err)
+	assert.Equal(ethcommon.BytesToAddress(ethcommon.FromHex(addr0)), keystoreInfo.address)
+	*ethKeystoreIndex = -1
+
+	// Address selection
+	*ethKeystoreAddress = addr1
+	keystoreInfo, err = parseEthKeystorePath(tempDir)
+	assert.Nil(err)
+	assert.Equal(ethcommon.BytesToAddress(ethcommon.FromHex(addr1)), keystoreInfo.address)
+}
+
+// TestDecryptPresaleKey checks decryptPresaleKey against a fixture whose
+// encseed, privkey and ethaddr were all computed independently of this
+// package: the ciphertext was produced with the openssl CLI
+// (aes-128-cbc, -nopad) over a fixed 32-byte seed, and the expected private
+// key/address were derived with standalone Keccak-256 and secp256k1
+// implementations, not decryptPresaleKey's own keccak256/addressFromPrivateKey.
+// A fixture built by round-tripping through this package's own encrypt/hash
+// calls (as a prior version of this test did) would pass for any
+// self-consistent derivation, including a wrong one (e.g. PKCS7-unpadding the
+// plaintext before hashing, as ethereumjs-wallet's raw-CBC format must not).
+func TestDecryptPresaleKey(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+	tempDir := t.TempDir()
+
+	const (
+		password = "testpassword123"
+		// iv (16 bytes) || ciphertext (16 bytes), from:
+		//   openssl enc -aes-128-cbc -K a590a7fc88b88db451b5936882d591b7 \
+		//     -iv 000102030405060708090a0b0c0d0e0f -nopad -in seed.bin
+		// where seed.bin is bytes 0x01..0x20 and the key is
+		// PBKDF2-HMAC-SHA256(password, password, 2000, dklen=16).
+		encSeed         = "000102030405060708090a0b0c0d0e0f4c17225817b42e51cd8ab7a9b4c80af44e1bb607f7432a8b7b1a01acdc0f22c9"
+		expectedAddrHex = "262eca031f7c5e27c9f7ced0d8acd69b4432fe69"
+	)
+	expectedPrivKey, err := hex.DecodeString("52b3f53ff196a28e7d2d01283ef9427070bda64128fb5630b97b6ab17a8ff0a8")
+	require.NoError(err)
+	expectedAddr := ethcommon.HexToAddress(expectedAddrHex)
+
+	contents := `{"encseed":"` + encSeed + `","ethaddr":"` + expectedAddrHex + `","email":"satoshi@example.com","btcaddr":"1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa"}`
+
+	keyfile := filepath.Join(tempDir, "presale-wallet.json")
+	require.NoError(os.WriteFile(keyfile, []byte(contents), 0644))
+
+	kind, err := keyfileKind(keyfile)
+	require.NoError(err)
+	assert.Equal(kindPresale, kind)
+
+	privKey, addr, err := decryptPresaleKey(keyfile, password)
+	require.NoError(err)
+	assert.Equal(expectedPrivKey, privKey)
+	assert.Equal(expectedAddr, addr)
+}
+
 // Keystore file exists, but address cannot be parsed
 func TestParse_ParseEthKeystorePathInvalidJSON(t *testing.T) {
 	assert := assert.New(t)
@@ -176,6 +293,115 @@ func TestParse_ParseEthKeystorePathInvalidJSON(t *testing.T) {
 	assert.True(err.Error() == "error parsing address from keyfile")
 }
 
+func TestConfig_BroadcastersTableMatchesJSONPrices(t *testing.T) {
+	assert := assert.New(t)
+	tempDir := t.TempDir()
+
+	configContents := `
+ethKeystorePath = "/tmp/keystore"
+
+[[broadcasters]]
+ethaddress = "0x0000000000000000000000000000000000000000"
+priceperunit = 1000
+pixelsperunit = 1
+
+[[broadcasters]]
+ethaddress = "0x1000000000000000000000000000000000000000"
+priceperunit = 2000
+pixelsperunit = 3
+`
+	configFile := filepath.Join(tempDir, "livepeer.toml")
+	require.NoError(t, os.WriteFile(configFile, []byte(configContents), 0644))
+
+	cfg, md, err := LoadConfig(configFile)
+	assert.Nil(err)
+	assert.True(md.IsDefined("ethKeystorePath"))
+	assert.Equal("/tmp/keystore", cfg.EthKeystorePath)
+
+	j := `{"broadcasters":[{"ethaddress":"0x0000000000000000000000000000000000000000","priceperunit":1000,"pixelsperunit":1}, {"ethaddress":"0x1000000000000000000000000000000000000000","priceperunit":2000,"pixelsperunit":3}]}`
+	jsonPrices := getBroadcasterPrices(j)
+
+	assert.Equal(jsonPrices, cfg.Broadcasters)
+}
+
+func TestMergeConfig_PresenceNotTruthinessGatesOverride(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	tempDir := t.TempDir()
+
+	defer func() { *ethKeystoreAddress = "" }()
+
+	// Field present in the file, explicitly set to its zero value: mergeConfig
+	// must overwrite the existing flag value rather than skip it because
+	// cfg.EthKeystoreAddress == "".
+	*ethKeystoreAddress = "preexisting"
+	configFile := filepath.Join(tempDir, "present.toml")
+	require.NoError(os.WriteFile(configFile, []byte(`ethKeystoreAddress = ""`), 0644))
+
+	cfg, md, err := LoadConfig(configFile)
+	require.NoError(err)
+	mergeConfig(cfg, md)
+	assert.Empty(*ethKeystoreAddress)
+
+	// Field absent from the file entirely: mergeConfig must leave the
+	// existing flag value untouched.
+	*ethKeystoreAddress = "preexisting"
+	emptyConfigFile := filepath.Join(tempDir, "absent.toml")
+	require.NoError(os.WriteFile(emptyConfigFile, []byte(``), 0644))
+
+	cfg, md, err = LoadConfig(emptyConfigFile)
+	require.NoError(err)
+	mergeConfig(cfg, md)
+	assert.Equal("preexisting", *ethKeystoreAddress)
+}
+
+func TestMergeConfig_BroadcastersNotAccumulatedAcrossCalls(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	tempDir := t.TempDir()
+
+	defer func() { *pricePerBroadcaster = "" }()
+
+	configFile := filepath.Join(tempDir, "broadcasters.toml")
+	contents := `
+[[broadcasters]]
+ethaddress = "0x0000000000000000000000000000000000000000"
+priceperunit = 1000
+pixelsperunit = 1
+`
+	require.NoError(os.WriteFile(configFile, []byte(contents), 0644))
+
+	cfg, md, err := LoadConfig(configFile)
+	require.NoError(err)
+
+	mergeConfig(cfg, md)
+	mergeConfig(cfg, md)
+
+	assert.Len(effectiveBroadcasterPrices, 1)
+}
+
+// TestMergeConfig_EthKeystoreIndexZeroValueOverrides checks that an
+// ethKeystoreIndex of 0 set in the config file overrides the flag's -1
+// sentinel default. -ethKeystoreIndex uses 0 (a valid index) as its
+// meaningful zero value, so mergeConfig must gate on md.IsDefined rather than
+// cfg.EthKeystoreIndex being non-zero or positive.
+func TestMergeConfig_EthKeystoreIndexZeroValueOverrides(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	tempDir := t.TempDir()
+
+	defer func() { *ethKeystoreIndex = -1 }()
+
+	configFile := filepath.Join(tempDir, "index.toml")
+	require.NoError(os.WriteFile(configFile, []byte(`ethKeystoreIndex = 0`), 0644))
+
+	cfg, md, err := LoadConfig(configFile)
+	require.NoError(err)
+	mergeConfig(cfg, md)
+
+	assert.Equal(0, *ethKeystoreIndex)
+}
+
 // Keystore path or file doesn't exist
 func TestParse_ParseEthKeystorePathFileNotFound(t *testing.T) {
 	assert := assert.New(t)